@@ -0,0 +1,300 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"math"
+	"sort"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// AnimationState tracks the runtime playback cursor for a single Animation.
+// It caches, per channel, the last keyframe index that was used so that
+// repeated calls to Animator.Sample with a monotonically increasing time
+// don't have to re-scan the keyframe slices from the start every time.
+type AnimationState struct {
+	// Animation is the animation this state is sampling.
+	Animation *Animation
+
+	// cursors holds one cached keyframe index set per entry in Animation.Channels.
+	cursors []channelCursor
+}
+
+// channelCursor caches the last keyframe index found for each of the three
+// keyframe tracks of an AnimationChannel.
+type channelCursor struct {
+	posIndex   int
+	scaleIndex int
+	rotIndex   int
+}
+
+// NewAnimationState creates a new playback state for anim, ready to be
+// passed to Animator.Sample.
+func NewAnimationState(anim *Animation) *AnimationState {
+	return &AnimationState{
+		Animation: anim,
+		cursors:   make([]channelCursor, len(anim.Channels)),
+	}
+}
+
+// Animator evaluates Animations defined for a Mesh and produces per-bone
+// matrix palettes suitable for uploading to a skinning vertex shader.
+//
+// An Animator is built once for a Mesh and reused across Sample calls for
+// any of that mesh's Animations; the bone hierarchy is only walked and
+// topologically sorted once, at construction time.
+type Animator struct {
+	// Mesh is the mesh whose Bones are being animated.
+	Mesh *Mesh
+
+	// boneOrder lists Mesh.Bones.Id in parent-before-child order, so that
+	// Sample can accumulate global transforms in a single pass.
+	boneOrder []int32
+
+	// nameToId maps a Bone.Name to its Bone.Id, since AnimationChannels
+	// reference bones by name.
+	nameToId map[string]int32
+
+	// idToIndex maps a Bone.Id to its index in Mesh.Bones.
+	idToIndex map[int32]int
+}
+
+// NewAnimator builds an Animator for mesh, precomputing a topologically
+// sorted bone order so that Sample doesn't need to re-derive it on every call.
+func NewAnimator(mesh *Mesh) *Animator {
+	a := &Animator{
+		Mesh:      mesh,
+		nameToId:  make(map[string]int32, len(mesh.Bones)),
+		idToIndex: make(map[int32]int, len(mesh.Bones)),
+	}
+	for i, b := range mesh.Bones {
+		a.nameToId[b.Name] = b.Id
+		a.idToIndex[b.Id] = i
+	}
+	a.boneOrder = sortBonesParentFirst(mesh.Bones)
+	return a
+}
+
+// sortBonesParentFirst returns the Id of every bone in bones ordered so that
+// a bone's parent always appears before it -- i.e. root bones (Parent == -1)
+// first, followed by their children, grandchildren and so on.
+func sortBonesParentFirst(bones []Bone) []int32 {
+	idToIndex := make(map[int32]int, len(bones))
+	for i, b := range bones {
+		idToIndex[b.Id] = i
+	}
+
+	depth := make([]int, len(bones))
+	var depthOf func(i int) int
+	depthOf = func(i int) int {
+		if depth[i] != 0 {
+			return depth[i]
+		}
+		b := bones[i]
+		parentIndex, hasParent := idToIndex[b.Parent]
+		if b.Parent < 0 || !hasParent {
+			depth[i] = 1
+			return depth[i]
+		}
+		depth[i] = depthOf(parentIndex) + 1
+		return depth[i]
+	}
+
+	order := make([]int32, len(bones))
+	for i, b := range bones {
+		depthOf(i)
+		order[i] = b.Id
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return depth[idToIndex[order[i]]] < depth[idToIndex[order[j]]]
+	})
+	return order
+}
+
+// Sample evaluates state's animation at time t, specified in seconds, and
+// returns a matrix palette indexed by Bone.Id -- ready to upload to a
+// skinning vertex shader that reads VertexWeightIds/VertexWeights.
+//
+// t is converted to ticks via Animation.TicksPerSecond and then wrapped by
+// Animation.Duration, so callers can pass a monotonically increasing time
+// and get looping playback for free.
+func (a *Animator) Sample(state *AnimationState, t float32) []mgl.Mat4 {
+	trs := a.sampleTRS(state, t)
+	locals := make(map[int32]mgl.Mat4, len(trs))
+	for boneID, v := range trs {
+		locals[boneID] = composeTRS(v)
+	}
+	return a.buildPalette(locals)
+}
+
+// boneTRS is a bone's local transform decomposed into its translation,
+// rotation and scale components, which is the form poses need to be in to be
+// blended: positions/scales LERP and rotations SLERP.
+type boneTRS struct {
+	pos   mgl.Vec3
+	scale mgl.Vec3
+	rot   mgl.Quat
+}
+
+// composeTRS reassembles a bone's local transform matrix from its TRS
+// components as T*R*S.
+func composeTRS(v boneTRS) mgl.Mat4 {
+	return mgl.Translate3D(v.pos[0], v.pos[1], v.pos[2]).
+		Mul4(v.rot.Mat4()).
+		Mul4(mgl.Scale3D(v.scale[0], v.scale[1], v.scale[2]))
+}
+
+// sampleTRS evaluates state's animation at time t and returns the sampled
+// local TRS for every bone that has a matching AnimationChannel. Bones with
+// no channel (and therefore no entry in the returned map) should fall back
+// to their rest pose, i.e. Bone.Transform.
+func (a *Animator) sampleTRS(state *AnimationState, t float32) map[int32]boneTRS {
+	anim := state.Animation
+
+	ticksPerSecond := anim.TicksPerSecond
+	if ticksPerSecond <= 0 {
+		ticksPerSecond = 25
+	}
+	ticks := t * ticksPerSecond
+	if anim.Duration > 0 {
+		ticks = float32(math.Mod(float64(ticks), float64(anim.Duration)))
+		if ticks < 0 {
+			ticks += anim.Duration
+		}
+	}
+
+	trs := make(map[int32]boneTRS, len(anim.Channels))
+	for i := range anim.Channels {
+		ch := &anim.Channels[i]
+		boneID, ok := a.nameToId[ch.Name]
+		if !ok {
+			continue
+		}
+
+		cursor := &state.cursors[i]
+		trs[boneID] = boneTRS{
+			pos:   sampleVec3Key(ch.PositionKeys, ticks, &cursor.posIndex),
+			scale: sampleVec3Key(ch.ScaleKeys, ticks, &cursor.scaleIndex),
+			rot:   sampleQuatKey(ch.RotationKeys, ticks, &cursor.rotIndex),
+		}
+	}
+	return trs
+}
+
+// buildPalette walks the bone hierarchy in parent-before-child order,
+// accumulating global transforms from locals (falling back to a bone's rest
+// pose, Bone.Transform, for any bone missing from locals), and returns the
+// resulting matrix palette indexed by Bone.Id.
+func (a *Animator) buildPalette(locals map[int32]mgl.Mat4) []mgl.Mat4 {
+	palette := make([]mgl.Mat4, len(a.Mesh.Bones))
+	globals := make(map[int32]mgl.Mat4, len(a.Mesh.Bones))
+	for _, boneID := range a.boneOrder {
+		bone := &a.Mesh.Bones[a.idToIndex[boneID]]
+
+		local, ok := locals[boneID]
+		if !ok {
+			local = bone.Transform
+		}
+
+		global := local
+		if bone.Parent >= 0 {
+			if parentGlobal, ok := globals[bone.Parent]; ok {
+				global = parentGlobal.Mul4(local)
+			}
+		}
+		globals[boneID] = global
+
+		if int(boneID) < len(palette) {
+			palette[boneID] = global.Mul4(bone.Offset)
+		}
+	}
+
+	return palette
+}
+
+// sampleVec3Key interpolates keys at the given time in ticks, LERPing
+// between the surrounding pair, and caches the keyframe index it settled on
+// in cursor so the next (presumably later) call starts its search there.
+func sampleVec3Key(keys []AnimationVec3Key, ticks float32, cursor *int) mgl.Vec3 {
+	switch len(keys) {
+	case 0:
+		return mgl.Vec3{0, 0, 0}
+	case 1:
+		return keys[0].Key
+	}
+
+	i := seekKeyIndex(*cursor, len(keys), ticks, func(idx int) float32 { return keys[idx].Time })
+	*cursor = i
+	if i >= len(keys)-1 {
+		return keys[len(keys)-1].Key
+	}
+
+	a, b := keys[i], keys[i+1]
+	f := keyFraction(a.Time, b.Time, ticks)
+	return lerpVec3(a.Key, b.Key, f)
+}
+
+// sampleQuatKey interpolates keys at the given time in ticks, SLERPing
+// between the surrounding pair to avoid the gimbal artifacts a component-wise
+// LERP of rotations would produce.
+func sampleQuatKey(keys []AnimationQuatKey, ticks float32, cursor *int) mgl.Quat {
+	switch len(keys) {
+	case 0:
+		return mgl.QuatIdent()
+	case 1:
+		return keys[0].Key
+	}
+
+	i := seekKeyIndex(*cursor, len(keys), ticks, func(idx int) float32 { return keys[idx].Time })
+	*cursor = i
+	if i >= len(keys)-1 {
+		return keys[len(keys)-1].Key
+	}
+
+	a, b := keys[i], keys[i+1]
+	f := keyFraction(a.Time, b.Time, ticks)
+	return mgl.QuatSlerp(a.Key, b.Key, f)
+}
+
+// seekKeyIndex finds the index i such that keyTime(i) <= ticks < keyTime(i+1),
+// starting the search from the cached index. If ticks has gone backwards
+// relative to the cached index -- typically because the animation looped --
+// the search restarts from the front.
+func seekKeyIndex(cached, n int, ticks float32, keyTime func(int) float32) int {
+	i := cached
+	if i < 0 || i >= n {
+		i = 0
+	}
+	if i > 0 && keyTime(i) > ticks {
+		i = 0
+	}
+	for i < n-1 && keyTime(i+1) <= ticks {
+		i++
+	}
+	return i
+}
+
+// keyFraction returns how far ticks sits between startTime and endTime, as a
+// value in [0, 1].
+func keyFraction(startTime, endTime, ticks float32) float32 {
+	span := endTime - startTime
+	if span <= 0 {
+		return 0
+	}
+	f := (ticks - startTime) / span
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// lerpVec3 linearly interpolates between a and b by f, where f is expected
+// to be in the range [0, 1].
+func lerpVec3(a, b mgl.Vec3, f float32) mgl.Vec3 {
+	return a.Mul(1 - f).Add(b.Mul(f))
+}
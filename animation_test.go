@@ -0,0 +1,140 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSeekKeyIndex(t *testing.T) {
+	times := []float32{0, 1, 2, 3}
+	keyTime := func(i int) float32 { return times[i] }
+
+	cases := []struct {
+		name   string
+		cached int
+		ticks  float32
+		want   int
+	}{
+		{"start", 0, 0, 0},
+		{"midway", 0, 1.5, 1},
+		{"cached advance", 1, 2.5, 2},
+		{"clamp to last key", 0, 10, 3},
+		{"loop wraps backward", 3, 0.5, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := seekKeyIndex(c.cached, len(times), c.ticks, keyTime)
+			if got != c.want {
+				t.Errorf("seekKeyIndex(%d, %v) = %d, want %d", c.cached, c.ticks, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLerpVec3(t *testing.T) {
+	a := mgl.Vec3{0, 0, 0}
+	b := mgl.Vec3{10, 20, 30}
+
+	cases := []struct {
+		f    float32
+		want mgl.Vec3
+	}{
+		{0, a},
+		{1, b},
+		{0.5, mgl.Vec3{5, 10, 15}},
+	}
+	for _, c := range cases {
+		if got := lerpVec3(a, b, c.f); !vec3ApproxEqual(got, c.want) {
+			t.Errorf("lerpVec3(f=%v) = %v, want %v", c.f, got, c.want)
+		}
+	}
+}
+
+func TestAnimatorSampleInterpolatesPosition(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+	anim := &Animation{
+		Duration:       2,
+		TicksPerSecond: 1,
+		Channels: []AnimationChannel{
+			{
+				Name: "root",
+				PositionKeys: []AnimationVec3Key{
+					{Time: 0, Key: mgl.Vec3{0, 0, 0}},
+					{Time: 2, Key: mgl.Vec3{2, 0, 0}},
+				},
+				ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+				RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+			},
+		},
+	}
+
+	animator := NewAnimator(mesh)
+	state := NewAnimationState(anim)
+
+	// Halfway through a 2-second/2-tick animation should land halfway
+	// between the two position keys.
+	palette := animator.Sample(state, 1)
+	got := palette[0].Col(3)
+	want := mgl.Vec4{1, 0, 0, 1}
+	if !vec4ApproxEqual(got, want) {
+		t.Errorf("Sample(t=1) translation = %v, want %v", got, want)
+	}
+}
+
+func TestAnimatorSampleLoopsPastDuration(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+	anim := &Animation{
+		Duration:       2,
+		TicksPerSecond: 1,
+		Channels: []AnimationChannel{
+			{
+				Name: "root",
+				PositionKeys: []AnimationVec3Key{
+					{Time: 0, Key: mgl.Vec3{0, 0, 0}},
+					{Time: 2, Key: mgl.Vec3{2, 0, 0}},
+				},
+				ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+				RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+			},
+		},
+	}
+
+	animator := NewAnimator(mesh)
+
+	// t=1 and t=3 (one full 2-second loop later) should sample the same pose.
+	first := animator.Sample(NewAnimationState(anim), 1)[0].Col(3)
+	second := animator.Sample(NewAnimationState(anim), 3)[0].Col(3)
+	if !vec4ApproxEqual(first, second) {
+		t.Errorf("Sample(t=1) = %v, Sample(t=3) = %v, want equal (looping)", first, second)
+	}
+}
+
+func vec3ApproxEqual(a, b mgl.Vec3) bool {
+	const eps = 1e-5
+	return absF(a[0]-b[0]) < eps && absF(a[1]-b[1]) < eps && absF(a[2]-b[2]) < eps
+}
+
+func vec4ApproxEqual(a, b mgl.Vec4) bool {
+	const eps = 1e-5
+	return absF(a[0]-b[0]) < eps && absF(a[1]-b[1]) < eps && absF(a[2]-b[2]) < eps && absF(a[3]-b[3]) < eps
+}
+
+func absF(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,183 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"fmt"
+	"math"
+)
+
+// BakedClip records where one animation's baked frames live within a
+// BakedAnimationSet's atlas: rows [StartRow, StartRow+BoneCount) hold that
+// animation's per-bone matrices, one 4-texel-wide column per sampled frame.
+type BakedClip struct {
+	// Name is the Animation.Name this clip was baked from.
+	Name string
+
+	// StartRow is the first row of the atlas this clip's bones occupy.
+	StartRow int
+
+	// BoneCount is the number of rows this clip occupies, starting at StartRow.
+	BoneCount int
+
+	// FrameCount is the number of sampled frames this clip has -- columns
+	// [0, FrameCount) hold real data; any columns beyond that (up to the
+	// atlas's Width, if a sibling clip needed more) are zeroed and unused.
+	FrameCount int
+
+	// SamplesPerSecond is the rate the clip was baked at, needed to convert
+	// a playback time back into a frame/column index.
+	SamplesPerSecond float32
+}
+
+// BakedAnimationSet is multiple animations' baked matrix palettes packed
+// into a single floating-point texture atlas, so a renderer doing
+// instanced/crowd rendering (foliage sway, particle-driven characters, ...)
+// only needs to bind one texture no matter how many clips an instance might
+// play.
+//
+// Atlas layout: row = bone index within a clip's block, column = sampled
+// frame, and each bone's 4x4 matrix is stored as 4 consecutive RGBA texels
+// (one per matrix column). A vertex shader fetches a bone's matrix for a
+// given clip and time with a texture lookup per matrix column, e.g.:
+//
+//	vec4 col0 = texture(animTex, vec2((frame*4+0 + 0.5) / texWidth, (startRow+boneId + 0.5) / texHeight));
+//	vec4 col1 = texture(animTex, vec2((frame*4+1 + 0.5) / texWidth, (startRow+boneId + 0.5) / texHeight));
+//	vec4 col2 = texture(animTex, vec2((frame*4+2 + 0.5) / texWidth, (startRow+boneId + 0.5) / texHeight));
+//	vec4 col3 = texture(animTex, vec2((frame*4+3 + 0.5) / texWidth, (startRow+boneId + 0.5) / texHeight));
+//	mat4 boneMatrix = mat4(col0, col1, col2, col3);
+//	// blend boneMatrix with the fetch at frame+1 by the sub-frame fraction for interpolation.
+type BakedAnimationSet struct {
+	// Data is the atlas's row-major RGBA texel data, ready to upload as a
+	// floating-point texture of size Width x Height.
+	Data []float32
+
+	// Width is the atlas's width in texels -- 4 times the widest clip's frame count.
+	Width int
+
+	// Height is the atlas's height in texels -- the sum of every clip's BoneCount.
+	Height int
+
+	// Clips locates each baked animation within Data.
+	Clips []BakedClip
+}
+
+// BakeToTexture pre-samples anim's per-bone world matrices, as produced for
+// a's Mesh, at samplesPerSecond and packs them into a single animation's
+// worth of the atlas layout BakedAnimationSet describes: row = bone index,
+// column = time sample. It's exposed on its own, in addition to
+// BakeAnimationSet, for callers that only ever need one clip's worth of
+// texture.
+//
+// BakeToTexture hangs off Animator rather than Animation because producing a
+// world-space matrix per bone requires the bone hierarchy and Offset
+// matrices that only a Mesh (via its Animator) has -- an Animation alone is
+// just a set of per-bone-name keyframe tracks. This is a deliberate
+// deviation from Animation.BakeToTexture(samplesPerSecond float32) as
+// originally requested, not an oversight: the signature changed because the
+// literal request was unbuildable without the Mesh it's baking against.
+func (a *Animator) BakeToTexture(anim *Animation, samplesPerSecond float32) (data []float32, width, height int, err error) {
+	if samplesPerSecond <= 0 {
+		return nil, 0, 0, fmt.Errorf("gombz: samplesPerSecond must be > 0")
+	}
+	if anim.Duration <= 0 {
+		return nil, 0, 0, fmt.Errorf("gombz: animation %q has no duration to bake", anim.Name)
+	}
+
+	ticksPerSecond := anim.TicksPerSecond
+	if ticksPerSecond <= 0 {
+		ticksPerSecond = 25
+	}
+	durationSeconds := anim.Duration / ticksPerSecond
+
+	frameCount := int(math.Ceil(float64(durationSeconds*samplesPerSecond))) + 1
+	if frameCount < 1 {
+		frameCount = 1
+	}
+
+	height = len(a.Mesh.Bones)
+	width = frameCount * 4
+	data = make([]float32, width*height*4)
+
+	state := NewAnimationState(anim)
+	for frame := 0; frame < frameCount; frame++ {
+		t := float32(frame) / samplesPerSecond
+		palette := a.Sample(state, t)
+		for boneID, m := range palette {
+			for col := 0; col < 4; col++ {
+				texelX := frame*4 + col
+				offset := (boneID*width + texelX) * 4
+				data[offset+0] = m[col*4+0]
+				data[offset+1] = m[col*4+1]
+				data[offset+2] = m[col*4+2]
+				data[offset+3] = m[col*4+3]
+			}
+		}
+	}
+
+	return data, width, height, nil
+}
+
+// BakeAnimationSet bakes every one of anims at samplesPerSecond and packs
+// them into a single BakedAnimationSet: each animation gets its own
+// vertical block of len(a.Mesh.Bones) rows, stacked top to bottom, with
+// Width set to the widest animation's frame count (in texels) so the atlas
+// stays rectangular -- a shorter clip's unused columns are left zeroed, and
+// BakedClip.FrameCount tells a consumer where to stop reading.
+func (a *Animator) BakeAnimationSet(anims []*Animation, samplesPerSecond float32) (*BakedAnimationSet, error) {
+	type bakedAnim struct {
+		clip  BakedClip
+		data  []float32
+		width int
+	}
+
+	baked := make([]bakedAnim, 0, len(anims))
+	maxWidth := 0
+	for _, anim := range anims {
+		data, width, height, err := a.BakeToTexture(anim, samplesPerSecond)
+		if err != nil {
+			return nil, fmt.Errorf("gombz: failed to bake %q: %v", anim.Name, err)
+		}
+		if width > maxWidth {
+			maxWidth = width
+		}
+		baked = append(baked, bakedAnim{
+			clip: BakedClip{
+				Name:             anim.Name,
+				BoneCount:        height,
+				FrameCount:       width / 4,
+				SamplesPerSecond: samplesPerSecond,
+			},
+			data:  data,
+			width: width,
+		})
+	}
+
+	totalRows := 0
+	for _, b := range baked {
+		totalRows += b.clip.BoneCount
+	}
+
+	set := &BakedAnimationSet{
+		Width:  maxWidth,
+		Height: totalRows,
+		Data:   make([]float32, maxWidth*totalRows*4),
+		Clips:  make([]BakedClip, len(baked)),
+	}
+
+	row := 0
+	for i, b := range baked {
+		b.clip.StartRow = row
+		set.Clips[i] = b.clip
+
+		for r := 0; r < b.clip.BoneCount; r++ {
+			srcOffset := r * b.width * 4
+			dstOffset := (row + r) * set.Width * 4
+			copy(set.Data[dstOffset:dstOffset+b.width*4], b.data[srcOffset:srcOffset+b.width*4])
+		}
+		row += b.clip.BoneCount
+	}
+
+	return set, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBakeToTextureLayout(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+	anim := &Animation{
+		Name:           "test",
+		Duration:       1,
+		TicksPerSecond: 1,
+		Channels: []AnimationChannel{
+			{
+				Name:         "root",
+				PositionKeys: []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{0, 0, 0}}},
+				ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+				RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+			},
+		},
+	}
+
+	animator := NewAnimator(mesh)
+	data, width, height, err := animator.BakeToTexture(anim, 1)
+	if err != nil {
+		t.Fatalf("BakeToTexture error: %v", err)
+	}
+	if height != 1 {
+		t.Fatalf("height = %d, want 1 (one bone)", height)
+	}
+	if want := 8; width != want { // ceil(1s * 1/s) + 1 = 2 frames, 4 texels/frame
+		t.Fatalf("width = %d, want %d", width, want)
+	}
+	if len(data) != width*height*4 {
+		t.Fatalf("len(data) = %d, want %d", len(data), width*height*4)
+	}
+
+	// Frame 0, column 0 should be the identity matrix's first column (1,0,0,0).
+	texel := data[0:4]
+	want := [4]float32{1, 0, 0, 0}
+	for i := range want {
+		if texel[i] != want[i] {
+			t.Errorf("frame 0 col 0 texel = %v, want %v", texel, want)
+		}
+	}
+}
+
+func TestBakeAnimationSetStacksClipsVertically(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+	makeAnim := func(name string, duration float32) *Animation {
+		return &Animation{
+			Name:           name,
+			Duration:       duration,
+			TicksPerSecond: 1,
+			Channels: []AnimationChannel{
+				{
+					Name:         "root",
+					PositionKeys: []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{0, 0, 0}}},
+					ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+					RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+				},
+			},
+		}
+	}
+
+	animator := NewAnimator(mesh)
+	set, err := animator.BakeAnimationSet([]*Animation{makeAnim("a", 1), makeAnim("b", 2)}, 1)
+	if err != nil {
+		t.Fatalf("BakeAnimationSet error: %v", err)
+	}
+
+	if len(set.Clips) != 2 {
+		t.Fatalf("len(Clips) = %d, want 2", len(set.Clips))
+	}
+	if set.Clips[0].StartRow != 0 || set.Clips[1].StartRow != 1 {
+		t.Errorf("Clips[*].StartRow = %d, %d, want 0, 1 (stacked one bone-row per clip)",
+			set.Clips[0].StartRow, set.Clips[1].StartRow)
+	}
+	if set.Height != 2 {
+		t.Errorf("Height = %d, want 2 (1 bone per clip x 2 clips)", set.Height)
+	}
+	if set.Width != 12 { // the 2-second clip needs 3 frames * 4 texels
+		t.Errorf("Width = %d, want 12 (widest clip's frame count)", set.Width)
+	}
+	if set.Clips[0].FrameCount != 2 || set.Clips[1].FrameCount != 3 {
+		t.Errorf("Clips[*].FrameCount = %d, %d, want 2, 3", set.Clips[0].FrameCount, set.Clips[1].FrameCount)
+	}
+}
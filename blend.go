@@ -0,0 +1,164 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// BoneMask scales a blend layer's influence on a per-bone basis, mapping a
+// Bone.Id to a contribution in the range 0..1. A bone that has no entry
+// inherits the value of its nearest masked ancestor (or 1, if no ancestor is
+// masked either), so masking a layer to e.g. the upper body only requires
+// listing the spine bone where the split happens.
+type BoneMask map[int32]float32
+
+// weightFor resolves the mask's contribution for boneID by walking up the
+// bone hierarchy defined by a until an explicit entry is found.
+func (mask BoneMask) weightFor(a *Animator, boneID int32) float32 {
+	for {
+		if w, ok := mask[boneID]; ok {
+			return w
+		}
+		index, ok := a.idToIndex[boneID]
+		if !ok {
+			return 1
+		}
+		parent := a.Mesh.Bones[index].Parent
+		if parent < 0 {
+			return 1
+		}
+		boneID = parent
+	}
+}
+
+// BlendLayer is a single input to Blender.Blend: an animation state sampled
+// at Time, contributing Weight to the final pose, optionally restricted to
+// part of the skeleton by Mask.
+type BlendLayer struct {
+	// State is the animation playback state to sample.
+	State *AnimationState
+
+	// Time is the time, in seconds, to sample State at.
+	Time float32
+
+	// Weight is this layer's contribution relative to the other layers
+	// passed to the same Blend call. Weights are normalized across all
+	// layers, so they don't need to sum to 1 themselves.
+	Weight float32
+
+	// Mask restricts this layer's influence to part of the skeleton. A nil
+	// Mask applies the layer's weight uniformly to every bone.
+	Mask BoneMask
+}
+
+// Blender mixes the poses of two or more Animations sampled against the same
+// Animator at runtime, producing a single matrix palette.
+type Blender struct {
+	// Animator provides the bone hierarchy the blended layers are sampled against.
+	Animator *Animator
+}
+
+// NewBlender creates a Blender that mixes animation layers for the bones
+// described by a.
+func NewBlender(a *Animator) *Blender {
+	return &Blender{Animator: a}
+}
+
+// Blend samples every layer, combines the per-bone local TRS of each by its
+// (mask-scaled, normalized) weight -- LERPing positions/scales and SLERPing
+// rotations -- and returns the resulting matrix palette indexed by Bone.Id.
+//
+// Layers are folded pairwise in order: each bone's running blended TRS is
+// re-blended against the next layer's contribution with a weight
+// proportional to that layer's share of the total weight seen so far for
+// that bone. This is equivalent to a normalized N-way weighted average
+// without needing every layer's weight up front.
+func (b *Blender) Blend(layers []BlendLayer) []mgl.Mat4 {
+	a := b.Animator
+
+	type accum struct {
+		trs    boneTRS
+		weight float32
+	}
+	blended := make(map[int32]accum, len(a.Mesh.Bones))
+
+	for _, layer := range layers {
+		if layer.Weight <= 0 {
+			continue
+		}
+		for boneID, v := range a.sampleTRS(layer.State, layer.Time) {
+			w := layer.Weight
+			if layer.Mask != nil {
+				w *= layer.Mask.weightFor(a, boneID)
+			}
+			if w <= 0 {
+				continue
+			}
+
+			cur, exists := blended[boneID]
+			if !exists {
+				blended[boneID] = accum{trs: v, weight: w}
+				continue
+			}
+
+			newWeight := cur.weight + w
+			t := w / newWeight
+			blended[boneID] = accum{
+				trs: boneTRS{
+					pos:   lerpVec3(cur.trs.pos, v.pos, t),
+					scale: lerpVec3(cur.trs.scale, v.scale, t),
+					rot:   mgl.QuatSlerp(cur.trs.rot, v.rot, t),
+				},
+				weight: newWeight,
+			}
+		}
+	}
+
+	locals := make(map[int32]mgl.Mat4, len(blended))
+	for boneID, v := range blended {
+		locals[boneID] = composeTRS(v.trs)
+	}
+	return a.buildPalette(locals)
+}
+
+// Transition cross-fades the playback of two animation states over Duration
+// seconds, ramping From's weight from 1 to 0 and To's weight from 0 to 1.
+type Transition struct {
+	Blender  *Blender
+	From, To *AnimationState
+	Duration float32
+
+	elapsed float32
+}
+
+// Transition starts a cross-fade between from and to that completes after
+// duration seconds of Advance calls.
+func (b *Blender) Transition(from, to *AnimationState, duration float32) *Transition {
+	return &Transition{Blender: b, From: from, To: to, Duration: duration}
+}
+
+// Advance moves the transition forward by dt seconds and blends From
+// (sampled at fromTime) against To (sampled at toTime) at the resulting
+// point in the cross-fade. The returned bool reports whether the transition
+// has completed, i.e. To now has full weight.
+func (tr *Transition) Advance(dt, fromTime, toTime float32) ([]mgl.Mat4, bool) {
+	tr.elapsed += dt
+
+	w := float32(1)
+	if tr.Duration > 0 {
+		w = tr.elapsed / tr.Duration
+	}
+	if w > 1 {
+		w = 1
+	} else if w < 0 {
+		w = 0
+	}
+
+	palette := tr.Blender.Blend([]BlendLayer{
+		{State: tr.From, Time: fromTime, Weight: 1 - w},
+		{State: tr.To, Time: toTime, Weight: w},
+	})
+	return palette, w >= 1
+}
@@ -0,0 +1,125 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBlendTwoLayersEqualWeight(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+
+	makeAnim := func(x float32) *Animation {
+		return &Animation{
+			Duration:       1,
+			TicksPerSecond: 1,
+			Channels: []AnimationChannel{
+				{
+					Name:         "root",
+					PositionKeys: []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{x, 0, 0}}},
+					ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+					RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+				},
+			},
+		}
+	}
+
+	animator := NewAnimator(mesh)
+	blender := NewBlender(animator)
+
+	palette := blender.Blend([]BlendLayer{
+		{State: NewAnimationState(makeAnim(0)), Time: 0, Weight: 1},
+		{State: NewAnimationState(makeAnim(10)), Time: 0, Weight: 1},
+	})
+
+	got := palette[0].Col(3)
+	want := mgl.Vec4{5, 0, 0, 1}
+	if !vec4ApproxEqual(got, want) {
+		t.Errorf("Blend() equal-weight translation = %v, want %v", got, want)
+	}
+}
+
+func TestBlendSkipsZeroWeightLayers(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+	}
+	anim := &Animation{
+		Duration:       1,
+		TicksPerSecond: 1,
+		Channels: []AnimationChannel{
+			{
+				Name:         "root",
+				PositionKeys: []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{3, 0, 0}}},
+				ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+				RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+			},
+		},
+	}
+	zero := &Animation{
+		Duration:       1,
+		TicksPerSecond: 1,
+		Channels: []AnimationChannel{
+			{
+				Name:         "root",
+				PositionKeys: []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{99, 99, 99}}},
+				ScaleKeys:    []AnimationVec3Key{{Time: 0, Key: mgl.Vec3{1, 1, 1}}},
+				RotationKeys: []AnimationQuatKey{{Time: 0, Key: mgl.QuatIdent()}},
+			},
+		},
+	}
+
+	animator := NewAnimator(mesh)
+	blender := NewBlender(animator)
+
+	palette := blender.Blend([]BlendLayer{
+		{State: NewAnimationState(anim), Time: 0, Weight: 1},
+		{State: NewAnimationState(zero), Time: 0, Weight: 0},
+	})
+
+	got := palette[0].Col(3)
+	want := mgl.Vec4{3, 0, 0, 1}
+	if !vec4ApproxEqual(got, want) {
+		t.Errorf("Blend() with a zero-weight layer = %v, want %v (layer ignored)", got, want)
+	}
+}
+
+func TestBoneMaskWeightForInheritsFromParent(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1},
+			{Name: "child", Id: 1, Parent: 0},
+			{Name: "grandchild", Id: 2, Parent: 1},
+		},
+	}
+	animator := NewAnimator(mesh)
+	mask := BoneMask{0: 0.25}
+
+	for _, boneID := range []int32{0, 1, 2} {
+		if got := mask.weightFor(animator, boneID); got != 0.25 {
+			t.Errorf("weightFor(bone=%d) = %v, want 0.25 (inherited from root)", boneID, got)
+		}
+	}
+}
+
+func TestBoneMaskWeightForDefaultsToOneWhenUnmasked(t *testing.T) {
+	mesh := &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1},
+		},
+	}
+	animator := NewAnimator(mesh)
+	mask := BoneMask{}
+
+	if got := mask.weightFor(animator, 0); got != 1 {
+		t.Errorf("weightFor() with no mask entries = %v, want 1", got)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"math"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// AABB is an axis-aligned bounding box, stored as its minimum and maximum corners.
+type AABB struct {
+	Min mgl.Vec3
+	Max mgl.Vec3
+}
+
+// emptyAABB returns an AABB with no extent, such that Union/Extend treat it
+// as an identity value -- unlike the zero value of AABB, which is a
+// degenerate box at the origin and would otherwise pollute a fold.
+func emptyAABB() AABB {
+	inf := float32(math.Inf(1))
+	return AABB{Min: mgl.Vec3{inf, inf, inf}, Max: mgl.Vec3{-inf, -inf, -inf}}
+}
+
+// IsEmpty reports whether a contains no points.
+func (a AABB) IsEmpty() bool {
+	return a.Min[0] > a.Max[0]
+}
+
+// Extend returns the smallest AABB containing both a and p.
+func (a AABB) Extend(p mgl.Vec3) AABB {
+	if a.IsEmpty() {
+		return AABB{Min: p, Max: p}
+	}
+	return AABB{
+		Min: mgl.Vec3{minF(a.Min[0], p[0]), minF(a.Min[1], p[1]), minF(a.Min[2], p[2])},
+		Max: mgl.Vec3{maxF(a.Max[0], p[0]), maxF(a.Max[1], p[1]), maxF(a.Max[2], p[2])},
+	}
+}
+
+// Union returns the smallest AABB containing both a and b.
+func (a AABB) Union(b AABB) AABB {
+	if a.IsEmpty() {
+		return b
+	}
+	if b.IsEmpty() {
+		return a
+	}
+	return AABB{
+		Min: mgl.Vec3{minF(a.Min[0], b.Min[0]), minF(a.Min[1], b.Min[1]), minF(a.Min[2], b.Min[2])},
+		Max: mgl.Vec3{maxF(a.Max[0], b.Max[0]), maxF(a.Max[1], b.Max[1]), maxF(a.Max[2], b.Max[2])},
+	}
+}
+
+// Transform returns the AABB that contains every corner of a transformed by m.
+func (a AABB) Transform(m mgl.Mat4) AABB {
+	if a.IsEmpty() {
+		return a
+	}
+
+	corners := [8]mgl.Vec3{
+		{a.Min[0], a.Min[1], a.Min[2]},
+		{a.Max[0], a.Min[1], a.Min[2]},
+		{a.Min[0], a.Max[1], a.Min[2]},
+		{a.Max[0], a.Max[1], a.Min[2]},
+		{a.Min[0], a.Min[1], a.Max[2]},
+		{a.Max[0], a.Min[1], a.Max[2]},
+		{a.Min[0], a.Max[1], a.Max[2]},
+		{a.Max[0], a.Max[1], a.Max[2]},
+	}
+
+	result := emptyAABB()
+	for _, c := range corners {
+		transformed := m.Mul4x1(mgl.Vec4{c[0], c[1], c[2], 1})
+		result = result.Extend(mgl.Vec3{transformed[0], transformed[1], transformed[2]})
+	}
+	return result
+}
+
+func minF(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxF(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// PrecomputeBoneBounds fills in mesh.BoneBounds with each bone's rest-pose
+// AABB, derived from the rest-pose (mesh.Vertices) positions of the vertices
+// VertexWeightIds/VertexWeights assign to that bone. Call this once after a
+// mesh's skinning data is populated; the result is stored on the Mesh so it
+// survives an Encode/DecodeMesh round-trip.
+func (mesh *Mesh) PrecomputeBoneBounds() {
+	if len(mesh.Bones) == 0 || len(mesh.VertexWeightIds) != len(mesh.Vertices) {
+		mesh.BoneBounds = nil
+		return
+	}
+
+	bounds := make([]AABB, len(mesh.Bones))
+	for i := range bounds {
+		bounds[i] = emptyAABB()
+	}
+
+	for v, vertex := range mesh.Vertices {
+		ids := mesh.VertexWeightIds[v]
+		weights := mesh.VertexWeights[v]
+		for i := 0; i < 4; i++ {
+			if weights[i] <= 0 {
+				continue
+			}
+			boneID := int(ids[i])
+			if boneID < 0 || boneID >= len(bounds) {
+				continue
+			}
+			bounds[boneID] = bounds[boneID].Extend(vertex)
+		}
+	}
+
+	mesh.BoneBounds = bounds
+}
+
+// ComputeSkinnedBounds returns the AABB of mesh under palette, computed by
+// transforming each bone's precomputed rest-pose AABB (see
+// PrecomputeBoneBounds) by its matrix in palette and taking the union.
+// This is O(bones) instead of the O(vertices) a full skin-and-measure pass
+// would cost, which is what makes it cheap enough for per-frame culling.
+func (mesh *Mesh) ComputeSkinnedBounds(palette []mgl.Mat4) AABB {
+	result := emptyAABB()
+	for boneID, bounds := range mesh.BoneBounds {
+		if bounds.IsEmpty() || boneID >= len(palette) {
+			continue
+		}
+		result = result.Union(bounds.Transform(palette[boneID]))
+	}
+	return result
+}
+
+// SkinCPU computes the skinned position of every vertex in mesh under
+// palette -- v' = Σ weights[i] * (palette[ids[i]] * v) over
+// VertexWeightIds/VertexWeights -- and writes the results into out, which
+// must be at least len(mesh.Vertices) long. This lets a downstream engine
+// ray-pick or collide against an animated mesh without a GPU readback.
+//
+// If mesh has no skinning data (VertexWeightIds/VertexWeights are nil or
+// shorter than Vertices, as for any static, bone-less mesh), out is filled
+// with mesh's unskinned rest-pose positions instead.
+func (mesh *Mesh) SkinCPU(palette []mgl.Mat4, out []mgl.Vec3) {
+	if len(mesh.VertexWeightIds) != len(mesh.Vertices) || len(mesh.VertexWeights) != len(mesh.Vertices) {
+		copy(out, mesh.Vertices)
+		return
+	}
+
+	for v, vertex := range mesh.Vertices {
+		ids := mesh.VertexWeightIds[v]
+		weights := mesh.VertexWeights[v]
+
+		var skinned mgl.Vec3
+		for i := 0; i < 4; i++ {
+			w := weights[i]
+			if w <= 0 {
+				continue
+			}
+			boneID := int(ids[i])
+			if boneID < 0 || boneID >= len(palette) {
+				continue
+			}
+			transformed := palette[boneID].Mul4x1(mgl.Vec4{vertex[0], vertex[1], vertex[2], 1})
+			skinned = skinned.Add(mgl.Vec3{transformed[0], transformed[1], transformed[2]}.Mul(w))
+		}
+		out[v] = skinned
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+func skinnedTestMesh() *Mesh {
+	return &Mesh{
+		Bones: []Bone{
+			{Name: "root", Id: 0, Parent: -1, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+			{Name: "tip", Id: 1, Parent: 0, Offset: mgl.Ident4(), Transform: mgl.Ident4()},
+		},
+		Vertices:        []mgl.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {2, 2, 2}},
+		VertexWeightIds: []mgl.Vec4{{0, 0, 0, 0}, {0, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}},
+		VertexWeights:   []mgl.Vec4{{1, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}, {1, 0, 0, 0}},
+	}
+}
+
+func TestPrecomputeBoneBounds(t *testing.T) {
+	mesh := skinnedTestMesh()
+	mesh.PrecomputeBoneBounds()
+
+	if len(mesh.BoneBounds) != 2 {
+		t.Fatalf("len(BoneBounds) = %d, want 2", len(mesh.BoneBounds))
+	}
+
+	root := mesh.BoneBounds[0]
+	if root.Min != (mgl.Vec3{0, 0, 0}) || root.Max != (mgl.Vec3{1, 0, 0}) {
+		t.Errorf("BoneBounds[0] = %+v, want Min {0 0 0} Max {1 0 0}", root)
+	}
+
+	tip := mesh.BoneBounds[1]
+	if tip.Min != (mgl.Vec3{0, 1, 0}) || tip.Max != (mgl.Vec3{2, 2, 2}) {
+		t.Errorf("BoneBounds[1] = %+v, want Min {0 1 0} Max {2 2 2}", tip)
+	}
+}
+
+func TestPrecomputeBoneBoundsNoSkinningData(t *testing.T) {
+	mesh := &Mesh{
+		Bones:    []Bone{{Name: "root", Id: 0, Parent: -1}},
+		Vertices: []mgl.Vec3{{0, 0, 0}},
+	}
+	mesh.PrecomputeBoneBounds()
+
+	if mesh.BoneBounds != nil {
+		t.Errorf("BoneBounds = %+v, want nil for a mesh with no VertexWeightIds", mesh.BoneBounds)
+	}
+}
+
+func TestComputeSkinnedBounds(t *testing.T) {
+	mesh := skinnedTestMesh()
+	mesh.PrecomputeBoneBounds()
+
+	palette := []mgl.Mat4{
+		mgl.Ident4(),
+		mgl.Translate3D(10, 0, 0),
+	}
+	bounds := mesh.ComputeSkinnedBounds(palette)
+
+	want := AABB{Min: mgl.Vec3{0, 0, 0}, Max: mgl.Vec3{12, 2, 2}}
+	if bounds.Min != want.Min || bounds.Max != want.Max {
+		t.Errorf("ComputeSkinnedBounds() = %+v, want %+v", bounds, want)
+	}
+}
+
+func TestSkinCPU(t *testing.T) {
+	mesh := skinnedTestMesh()
+	palette := []mgl.Mat4{
+		mgl.Ident4(),
+		mgl.Translate3D(10, 0, 0),
+	}
+
+	out := make([]mgl.Vec3, len(mesh.Vertices))
+	mesh.SkinCPU(palette, out)
+
+	want := []mgl.Vec3{{0, 0, 0}, {1, 0, 0}, {10, 1, 0}, {12, 2, 2}}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("out[%d] = %v, want %v", i, out[i], want[i])
+		}
+	}
+}
+
+// TestSkinCPUFallsBackToRestPose verifies the no-skinning-data path: a
+// static, bone-less mesh's SkinCPU should just copy Vertices through
+// unchanged rather than panicking on the mismatched/absent weight slices.
+func TestSkinCPUFallsBackToRestPose(t *testing.T) {
+	mesh := &Mesh{
+		Vertices: []mgl.Vec3{{1, 2, 3}, {4, 5, 6}},
+	}
+
+	out := make([]mgl.Vec3, len(mesh.Vertices))
+	mesh.SkinCPU(nil, out)
+
+	for i := range mesh.Vertices {
+		if out[i] != mesh.Vertices[i] {
+			t.Errorf("out[%d] = %v, want rest-pose %v", i, out[i], mesh.Vertices[i])
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+//go:build assimp
+
+package main
+
+import (
+	"github.com/tbogdala/gombz"
+	"github.com/tbogdala/gombz/import"
+)
+
+func importAssimpAsset(path string) (*gombz.Mesh, error) {
+	return importer.ImportAssimp(path)
+}
@@ -0,0 +1,76 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Command gombz-import batch-converts DCC-exported assets (COLLADA, FBX,
+// glTF 2.0) into .gombz files using the importer package and gombz's own
+// encoder.
+//
+// Usage:
+//
+//	gombz-import [-out DIR] file.gltf file.dae ...
+//
+// Build with `-tags assimp` (and the assimp library available) to add
+// COLLADA/FBX support; without it only glTF 2.0 input is accepted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tbogdala/gombz"
+	_ "github.com/tbogdala/gombz/codec/bsonzlib"
+	"github.com/tbogdala/gombz/import"
+)
+
+func main() {
+	outDir := flag.String("out", ".", "directory to write .gombz files to")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: gombz-import [-out DIR] file.gltf file.dae ...")
+		os.Exit(2)
+	}
+
+	for _, path := range flag.Args() {
+		if err := convert(path, *outDir); err != nil {
+			log.Printf("%s: %v", path, err)
+		}
+	}
+}
+
+// convert imports the asset at path and writes it alongside -out as a
+// .gombz file with the same base name.
+func convert(path, outDir string) error {
+	mesh, err := importAsset(path)
+	if err != nil {
+		return err
+	}
+
+	outPath := filepath.Join(outDir, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+".gombz")
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %v", outPath, err)
+	}
+	defer f.Close()
+
+	if err := mesh.Encode(f, gombz.CodecBsonZlib); err != nil {
+		return fmt.Errorf("failed to encode: %v", err)
+	}
+
+	log.Printf("wrote %s", outPath)
+	return nil
+}
+
+// importAsset dispatches to the importer package based on path's extension.
+func importAsset(path string) (*gombz.Mesh, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gltf", ".glb":
+		return importer.ImportGLTF(path)
+	default:
+		return importAssimpAsset(path)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+//go:build !assimp
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/tbogdala/gombz"
+)
+
+func importAssimpAsset(path string) (*gombz.Mesh, error) {
+	return nil, fmt.Errorf("%s: COLLADA/FBX import requires building gombz-import with -tags assimp", path)
+}
@@ -0,0 +1,175 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package gombz
+
+import (
+	"bufio"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// magicHeader prefixes every file Encode writes, so DecodeMesh can tell
+	// it apart from the unversioned zlib+bson files gombz wrote before this
+	// header existed.
+	magicHeader = "GMBZ"
+
+	// formatVersion is the version of the header format itself, independent
+	// of which Codec a file was written with.
+	formatVersion uint16 = 1
+)
+
+// CodecBsonZlib is the id of the original zlib+bson wire format. It is
+// reserved, rather than assigned by whichever codec package happens to
+// import first, because DecodeMesh also uses it to pick a Codec for files
+// that have no header at all.
+const CodecBsonZlib uint16 = 0
+
+// Codec encodes and decodes a Mesh to and from a particular wire format.
+// Implementations live in codec subpackages (codec/bsonzlib, codec/gob,
+// codec/json, codec/msgpack) and make themselves available to Mesh.Encode
+// and DecodeMesh by calling RegisterCodec from an init function.
+type Codec interface {
+	Encode(w io.Writer, mesh *Mesh) error
+	Decode(r io.Reader) (*Mesh, error)
+}
+
+// Compressor wraps a stream compression scheme so a Codec's wire format can
+// be written once and compressed with whichever scheme a caller prefers --
+// zlib (see ZlibCompressor), zstd, lz4, or none at all.
+type Compressor interface {
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// ZlibCompressor implements Compressor using the standard library's
+// compress/zlib, at the best-compression level.
+type ZlibCompressor struct{}
+
+// NewWriter returns a zlib writer that writes its compressed output to w.
+func (ZlibCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriterLevel(w, zlib.BestCompression)
+}
+
+// NewReader returns a zlib reader that reads compressed data from r.
+func (ZlibCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+// CompressedCodec wraps another Codec, running its output through Compressor
+// before it reaches the underlying writer and the reverse on decode. This is
+// how a codec package that only knows its own serialization format (gob,
+// json, msgpack, ...) can be combined with any Compressor a caller wants,
+// without each codec package having to implement compression itself.
+type CompressedCodec struct {
+	Codec      Codec
+	Compressor Compressor
+}
+
+// Encode compresses mesh's encoding with c.Compressor and writes it to w.
+func (c CompressedCodec) Encode(w io.Writer, mesh *Mesh) error {
+	cw, err := c.Compressor.NewWriter(w)
+	if err != nil {
+		return err
+	}
+	if err := c.Codec.Encode(cw, mesh); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// Decode decompresses r with c.Compressor and decodes the result with c.Codec.
+func (c CompressedCodec) Decode(r io.Reader) (*Mesh, error) {
+	cr, err := c.Compressor.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer cr.Close()
+	return c.Codec.Decode(cr)
+}
+
+// registeredCodec pairs a Codec with the name it was registered under, so
+// that tools can print a human-readable format name from a codec id.
+type registeredCodec struct {
+	name  string
+	codec Codec
+}
+
+var codecRegistry = make(map[uint16]registeredCodec)
+
+// RegisterCodec makes codec available under id to Mesh.Encode and
+// DecodeMesh. Codec packages call this from an init function; importing a
+// codec package for its side effect (`import _ "github.com/tbogdala/gombz/codec/gob"`)
+// is what makes DecodeMesh able to read files written with it.
+func RegisterCodec(id uint16, name string, codec Codec) {
+	codecRegistry[id] = registeredCodec{name: name, codec: codec}
+}
+
+// Encode writes mesh to w using the Codec registered under codecID, prefixed
+// with a small versioned header (magicHeader + format version + codec id) so
+// DecodeMesh can identify how to read it back. See RegisterCodec for how a
+// codecID becomes available.
+func (mesh *Mesh) Encode(w io.Writer, codecID uint16) error {
+	rc, ok := codecRegistry[codecID]
+	if !ok {
+		return fmt.Errorf("gombz: no codec registered for id %d", codecID)
+	}
+
+	header := make([]byte, len(magicHeader)+4)
+	copy(header, magicHeader)
+	binary.BigEndian.PutUint16(header[len(magicHeader):], formatVersion)
+	binary.BigEndian.PutUint16(header[len(magicHeader)+2:], codecID)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	return rc.codec.Encode(w, mesh)
+}
+
+// DecodeMesh reads a Mesh from r, auto-detecting its format. Files written
+// by Encode start with the magicHeader bytes, a format version and the id
+// of the Codec used to write them; DecodeMesh reads that header and hands
+// the rest of the stream to whichever Codec was registered under that id.
+//
+// Files with no header at all are the original zlib+bson format gombz wrote
+// before this versioned header existed, and are decoded with the Codec
+// registered under CodecBsonZlib -- import "github.com/tbogdala/gombz/codec/bsonzlib"
+// for its side effect to be able to read those.
+func DecodeMesh(r io.Reader) (*Mesh, error) {
+	br := bufio.NewReader(r)
+
+	peeked, err := br.Peek(len(magicHeader))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if string(peeked) == magicHeader {
+		if _, err := br.Discard(len(magicHeader)); err != nil {
+			return nil, err
+		}
+
+		var versionAndCodec [4]byte
+		if _, err := io.ReadFull(br, versionAndCodec[:]); err != nil {
+			return nil, err
+		}
+		codecID := binary.BigEndian.Uint16(versionAndCodec[2:])
+
+		rc, ok := codecRegistry[codecID]
+		if !ok {
+			return nil, fmt.Errorf("gombz: no codec registered for id %d -- is it imported for its side effect?", codecID)
+		}
+		return rc.codec.Decode(br)
+	}
+
+	rc, ok := codecRegistry[CodecBsonZlib]
+	if !ok {
+		return nil, errors.New(`gombz: file has no format header and no legacy codec is registered -- ` +
+			`import "github.com/tbogdala/gombz/codec/bsonzlib" for its side effect to read pre-header files`)
+	}
+	return rc.codec.Decode(br)
+}
@@ -0,0 +1,63 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Package bsonzlib implements gombz's original wire format: the mesh
+// bson-marshaled and then zlib-compressed. It registers itself under
+// gombz.CodecBsonZlib, which is also the id gombz.DecodeMesh falls back to
+// for files that predate the versioned format header -- so importing this
+// package for its side effect is what lets DecodeMesh keep reading meshes
+// encoded before the Codec interface existed.
+package bsonzlib
+
+import (
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+
+	"github.com/tbogdala/gombz"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func init() {
+	gombz.RegisterCodec(gombz.CodecBsonZlib, "bsonzlib", Codec{})
+}
+
+// Codec implements gombz.Codec for the zlib+bson wire format.
+type Codec struct{}
+
+// Encode bson-marshals mesh and zlib-compresses the result to w.
+func (Codec) Encode(w io.Writer, mesh *gombz.Mesh) error {
+	bs, err := bson.Marshal(mesh)
+	if err != nil {
+		return err
+	}
+
+	gz, err := zlib.NewWriterLevel(w, zlib.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := gz.Write(bs); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Decode zlib-decompresses r and bson-unmarshals the result into a Mesh.
+func (Codec) Decode(r io.Reader) (*gombz.Mesh, error) {
+	gzReader, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzReader.Close()
+
+	decompBytes, err := ioutil.ReadAll(gzReader)
+	if err != nil {
+		return nil, err
+	}
+
+	mesh := new(gombz.Mesh)
+	if err := bson.Unmarshal(decompBytes, mesh); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Package gob implements a gombz.Codec using the standard library's
+// encoding/gob, for interop with pipelines that already speak gob. Combine
+// it with gombz.CompressedCodec if the output needs to be compressed.
+package gob
+
+import (
+	"encoding/gob"
+	"io"
+
+	"github.com/tbogdala/gombz"
+)
+
+// ID is the codec id this package registers itself under.
+const ID uint16 = 1
+
+func init() {
+	gombz.RegisterCodec(ID, "gob", Codec{})
+}
+
+// Codec implements gombz.Codec using encoding/gob.
+type Codec struct{}
+
+// Encode gob-encodes mesh to w.
+func (Codec) Encode(w io.Writer, mesh *gombz.Mesh) error {
+	return gob.NewEncoder(w).Encode(mesh)
+}
+
+// Decode gob-decodes a Mesh from r.
+func (Codec) Decode(r io.Reader) (*gombz.Mesh, error) {
+	mesh := new(gombz.Mesh)
+	if err := gob.NewDecoder(r).Decode(mesh); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Package json implements a gombz.Codec using the standard library's
+// encoding/json. It's intended for debugging and diffing meshes -- readable
+// at the cost of being far larger on disk than the binary codecs.
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/tbogdala/gombz"
+)
+
+// ID is the codec id this package registers itself under.
+const ID uint16 = 2
+
+func init() {
+	gombz.RegisterCodec(ID, "json", Codec{})
+}
+
+// Codec implements gombz.Codec using encoding/json.
+type Codec struct{}
+
+// Encode json-encodes mesh to w.
+func (Codec) Encode(w io.Writer, mesh *gombz.Mesh) error {
+	return json.NewEncoder(w).Encode(mesh)
+}
+
+// Decode json-decodes a Mesh from r.
+func (Codec) Decode(r io.Reader) (*gombz.Mesh, error) {
+	mesh := new(gombz.Mesh)
+	if err := json.NewDecoder(r).Decode(mesh); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Package msgpack implements a gombz.Codec using vmihailenco/msgpack, for
+// interop with pipelines that already speak msgpack. It's a more compact
+// wire format than codec/json while staying schema-less like it.
+package msgpack
+
+import (
+	"io"
+
+	"github.com/tbogdala/gombz"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ID is the codec id this package registers itself under.
+const ID uint16 = 3
+
+func init() {
+	gombz.RegisterCodec(ID, "msgpack", Codec{})
+}
+
+// Codec implements gombz.Codec using vmihailenco/msgpack.
+type Codec struct{}
+
+// Encode msgpack-encodes mesh to w.
+func (Codec) Encode(w io.Writer, mesh *gombz.Mesh) error {
+	return msgpack.NewEncoder(w).Encode(mesh)
+}
+
+// Decode msgpack-decodes a Mesh from r.
+func (Codec) Decode(r io.Reader) (*gombz.Mesh, error) {
+	mesh := new(gombz.Mesh)
+	if err := msgpack.NewDecoder(r).Decode(mesh); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
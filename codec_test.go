@@ -0,0 +1,63 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// This file lives in package gombz_test, rather than gombz, because it
+// exercises real codec implementations (codec/bsonzlib, codec/gob), which
+// import gombz -- an internal test file importing them back would be a
+// compile cycle.
+package gombz_test
+
+import (
+	"bytes"
+	"testing"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/gombz"
+	"github.com/tbogdala/gombz/codec/bsonzlib"
+	gobcodec "github.com/tbogdala/gombz/codec/gob"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	mesh := &gombz.Mesh{
+		VertexCount: 2,
+		Vertices:    []mgl.Vec3{{1, 2, 3}, {4, 5, 6}},
+	}
+
+	for _, codecID := range []uint16{gombz.CodecBsonZlib, gobcodec.ID} {
+		var buf bytes.Buffer
+		if err := mesh.Encode(&buf, codecID); err != nil {
+			t.Fatalf("Encode(codec=%d) error: %v", codecID, err)
+		}
+
+		got, err := gombz.DecodeMesh(&buf)
+		if err != nil {
+			t.Fatalf("DecodeMesh(codec=%d) error: %v", codecID, err)
+		}
+		if len(got.Vertices) != len(mesh.Vertices) || got.Vertices[0] != mesh.Vertices[0] || got.Vertices[1] != mesh.Vertices[1] {
+			t.Errorf("DecodeMesh(codec=%d) = %+v, want %+v", codecID, got.Vertices, mesh.Vertices)
+		}
+	}
+}
+
+// TestDecodeMeshFallsBackToLegacyFormat verifies that a file written without
+// Encode's versioned header -- i.e. the original zlib+bson format gombz wrote
+// before it existed -- still decodes correctly via the CodecBsonZlib fallback.
+func TestDecodeMeshFallsBackToLegacyFormat(t *testing.T) {
+	mesh := &gombz.Mesh{
+		VertexCount: 1,
+		Vertices:    []mgl.Vec3{{7, 8, 9}},
+	}
+
+	var buf bytes.Buffer
+	if err := (bsonzlib.Codec{}).Encode(&buf, mesh); err != nil {
+		t.Fatalf("legacy Encode error: %v", err)
+	}
+
+	got, err := gombz.DecodeMesh(&buf)
+	if err != nil {
+		t.Fatalf("DecodeMesh of a headerless (legacy) file errored: %v", err)
+	}
+	if len(got.Vertices) != 1 || got.Vertices[0] != mesh.Vertices[0] {
+		t.Errorf("DecodeMesh of legacy file = %+v, want %+v", got.Vertices, mesh.Vertices)
+	}
+}
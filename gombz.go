@@ -4,12 +4,7 @@
 package gombz
 
 import (
-	"bytes"
-	"compress/zlib"
-	"io/ioutil"
-
 	mgl "github.com/go-gl/mathgl/mgl32"
-	"gopkg.in/mgo.v2/bson"
 )
 
 const (
@@ -137,57 +132,16 @@ type Mesh struct {
 	// Animations is a slice of Animation objects that represent all animations that
 	// can deform the mesh's Bones.
 	Animations []Animation
-}
-
-// Encode takes a given mesh and encodes it to binary with bson
-// and then compresses it with zlib and returns the result -- or
-// returns a non-nil err on fail.
-func (mesh *Mesh) Encode() (out []byte, err error) {
-	// encode
-	bs, err := bson.Marshal(mesh)
-	if err != nil {
-		return nil, err
-	}
-
-	// compress
-	gzBuffer := new(bytes.Buffer)
-	gz, err := zlib.NewWriterLevel(gzBuffer, zlib.BestCompression)
-	if err != nil {
-		return nil, err
-	}
-	if _, err = gz.Write(bs); err != nil {
-		return nil, err
-	}
-	if err = gz.Close(); err != nil {
-		return nil, err
-	}
-
-	return gzBuffer.Bytes(), nil
-}
 
-// DecodeMesh takes a byte stream and decompresses it with zlib and
-// then decodes it with bson and returns the result -- or returns
-// a non-nil err on fail.
-func DecodeMesh(bs []byte) (outMesh *Mesh, err error) {
-	// load up the buffer
-	gzBuffer := bytes.NewBuffer(bs)
-
-	// decompress
-	gzReader, err := zlib.NewReader(gzBuffer)
-	if err != nil {
-		return nil, err
-	}
-	decompBytes, err := ioutil.ReadAll(gzReader)
-	if err != nil {
-		return nil, err
-	}
-
-	// decode
-	outMesh = new(Mesh)
-	err = bson.Unmarshal(decompBytes, outMesh)
-	if err != nil {
-		return nil, err
-	}
-
-	return outMesh, nil
+	// BoneBounds is a slice of per-bone rest-pose bounding boxes, indexed the
+	// same as the matrix palette produced by Animator.Sample (i.e. by
+	// Bone.Id). It is populated by PrecomputeBoneBounds and is nil until
+	// then; it is stored on the Mesh so it survives an encode/decode
+	// round-trip instead of having to be rebuilt on load.
+	BoneBounds []AABB
+
+	// BakedAnimations is an optional texture-ready bake of this mesh's
+	// Animations, produced by Animator.BakeAnimationSet for GPU
+	// vertex-texture skinning. It is nil unless a caller has baked one.
+	BakedAnimations *BakedAnimationSet
 }
@@ -0,0 +1,275 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+//go:build assimp
+
+package importer
+
+import (
+	"fmt"
+
+	"github.com/raedatoui/assimp"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/gombz"
+)
+
+// ImportAssimp loads any format Open Asset Import Library supports --
+// notably COLLADA (.dae) and FBX -- from path and converts its first mesh
+// into a gombz.Mesh, including skinning data and animations.
+//
+// This is built behind the "assimp" build tag because it links against the
+// assimp C++ library via cgo; build with `-tags assimp` and the library
+// available on the system to use it. glTF 2.0 assets don't need assimp at
+// all -- see ImportGLTF.
+func ImportAssimp(path string) (*gombz.Mesh, error) {
+	scene := assimp.ImportFile(path, uint(
+		assimp.Process_Triangulate|
+			assimp.Process_CalcTangentSpace|
+			assimp.Process_LimitBoneWeights|
+			assimp.Process_JoinIdenticalVertices))
+	if scene == nil {
+		return nil, fmt.Errorf("importer: assimp failed to import %q: %s", path, assimp.GetErrorString())
+	}
+	defer scene.ReleaseImport()
+
+	meshes := scene.Meshes()
+	if len(meshes) == 0 {
+		return nil, fmt.Errorf("importer: %q has no meshes", path)
+	}
+	aiMesh := meshes[0]
+
+	mesh := new(gombz.Mesh)
+	mesh.Vertices = toVec3Slice(aiMesh.Vertices())
+	mesh.VertexCount = uint32(len(mesh.Vertices))
+	mesh.Normals = toVec3Slice(aiMesh.Normals())
+	mesh.Tangents = toVec3Slice(aiMesh.Tangents())
+
+	for channel := 0; channel < gombz.MaxUVChannelCount; channel++ {
+		uvs := aiMesh.TextureCoords(channel)
+		if len(uvs) == 0 {
+			break
+		}
+		mesh.UVChannels[channel] = make([]mgl.Vec2, len(uvs))
+		for i, uv := range uvs {
+			mesh.UVChannels[channel][i] = mgl.Vec2{uv.X(), uv.Y()}
+		}
+	}
+
+	// Process_Triangulate only triangulates polygons with more than 3
+	// vertices -- point and line primitives (2 or 1 indices) pass through
+	// unchanged, so each face's index count must be checked rather than
+	// assumed to be 3.
+	faces := aiMesh.Faces()
+	mesh.Faces = make([]gombz.MeshFace, 0, len(faces))
+	for _, face := range faces {
+		idx := face.CopyIndices()
+		if len(idx) != 3 {
+			continue
+		}
+		mesh.Faces = append(mesh.Faces, gombz.MeshFace{idx[0], idx[1], idx[2]})
+	}
+	mesh.FaceCount = uint32(len(mesh.Faces))
+
+	if len(aiMesh.Bones()) > 0 {
+		// aiMesh is always meshes[0] (see above), so its index into
+		// scene.Meshes() -- what a node's Meshes() list refers to -- is 0.
+		if err := importAssimpSkeleton(scene, aiMesh, 0, mesh); err != nil {
+			return nil, err
+		}
+		mesh.BoneCount = uint32(len(mesh.Bones))
+		mesh.Animations = importAssimpAnimations(scene, mesh)
+	}
+
+	return mesh, nil
+}
+
+// findMeshNode does a depth-first search of root's subtree for the node that
+// references meshIndex (an index into scene.Meshes()), which is the node
+// aiMesh's vertices are authored relative to.
+func findMeshNode(root *assimp.Node, meshIndex int) *assimp.Node {
+	if root == nil {
+		return nil
+	}
+	for _, m := range root.Meshes() {
+		if int(m) == meshIndex {
+			return root
+		}
+	}
+	for _, child := range root.Children() {
+		if found := findMeshNode(child, meshIndex); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// importAssimpSkeleton walks aiMesh's bones and fills in mesh.Bones,
+// mesh.VertexWeightIds and mesh.VertexWeights.
+//
+// Unlike qmuntal/gltf (see ImportGLTF), the raedatoui/assimp binding doesn't
+// expose aiBone's offset (inverse-bind) matrix at all -- Bone has no
+// OffsetMatrix accessor, and the underlying C struct field isn't exported
+// outside package assimp. So each bone's offset -- the matrix taking a
+// mesh-space vertex into that bone's space at bind pose -- is derived the
+// long way instead of being read directly: find the bone's node and the
+// mesh's own node in the scene graph by name/index, and combine their
+// accumulated world transforms as boneWorld.Inv() * meshWorld.
+//
+// It's an error for a bone's name not to resolve to a scene node -- without
+// one there's no way to compute a correct Offset, and silently falling back
+// to an identity transform would produce a mesh that looks imported fine but
+// deforms wrong around that bone.
+func importAssimpSkeleton(scene *assimp.Scene, aiMesh *assimp.Mesh, meshIndex int, mesh *gombz.Mesh) error {
+	meshWorld := nodeWorldTransform(findMeshNode(scene.RootNode(), meshIndex))
+
+	bones := aiMesh.Bones()
+	nodes := make([]sceneNode, len(bones))
+	for i, aiBone := range bones {
+		node := findNode(scene.RootNode(), aiBone.Name())
+		if node == nil {
+			return fmt.Errorf("importer: bone %q has no matching scene node", aiBone.Name())
+		}
+
+		parentName := ""
+		if node.Parent() != nil {
+			parentName = node.Parent().Name()
+		}
+		nodes[i] = sceneNode{
+			Name:      aiBone.Name(),
+			Parent:    parentName,
+			Offset:    nodeWorldTransform(node).Inv().Mul4(meshWorld),
+			Transform: toMat4(node.Transformation()),
+		}
+	}
+	mesh.Bones = assignBoneIDs(nodes)
+
+	influences := make([][]vertexInfluence, len(mesh.Vertices))
+	for _, aiBone := range bones {
+		boneID := mesh.Bones[boneIndexForNodeName(mesh.Bones, aiBone.Name())].Id
+		for _, w := range aiBone.Weights() {
+			influences[w.VertexId()] = append(influences[w.VertexId()], vertexInfluence{
+				BoneID: boneID,
+				Weight: w.Weight(),
+			})
+		}
+	}
+
+	mesh.VertexWeightIds = make([]mgl.Vec4, len(mesh.Vertices))
+	mesh.VertexWeights = make([]mgl.Vec4, len(mesh.Vertices))
+	for v, inf := range influences {
+		mesh.VertexWeightIds[v], mesh.VertexWeights[v] = topFourWeights(inf)
+	}
+	return nil
+}
+
+// importAssimpAnimations converts every aiAnimation whose channels target
+// one of mesh's bones into a gombz.Animation.
+func importAssimpAnimations(scene *assimp.Scene, mesh *gombz.Mesh) []gombz.Animation {
+	boneSet := make(map[string]bool, len(mesh.Bones))
+	for _, b := range mesh.Bones {
+		boneSet[b.Name] = true
+	}
+
+	aiAnims := scene.Animations()
+	animations := make([]gombz.Animation, 0, len(aiAnims))
+	for _, aiAnim := range aiAnims {
+		channels := make([]gombz.AnimationChannel, 0, len(aiAnim.Channels()))
+		for _, aiChannel := range aiAnim.Channels() {
+			if !boneSet[aiChannel.Name()] {
+				continue
+			}
+
+			gc := gombz.AnimationChannel{Name: aiChannel.Name()}
+			for _, key := range aiChannel.PositionKeys() {
+				v := key.Value()
+				gc.PositionKeys = append(gc.PositionKeys, gombz.AnimationVec3Key{
+					Time: float32(key.Time()),
+					Key:  mgl.Vec3{v.X(), v.Y(), v.Z()},
+				})
+			}
+			for _, key := range aiChannel.ScalingKeys() {
+				v := key.Value()
+				gc.ScaleKeys = append(gc.ScaleKeys, gombz.AnimationVec3Key{
+					Time: float32(key.Time()),
+					Key:  mgl.Vec3{v.X(), v.Y(), v.Z()},
+				})
+			}
+			for _, key := range aiChannel.RotationKeys() {
+				q := key.Value()
+				gc.RotationKeys = append(gc.RotationKeys, gombz.AnimationQuatKey{
+					Time: float32(key.Time()),
+					Key:  mgl.Quat{W: q.W(), V: mgl.Vec3{q.X(), q.Y(), q.Z()}},
+				})
+			}
+			channels = append(channels, gc)
+		}
+
+		animations = append(animations, gombz.Animation{
+			Name:           aiAnim.Name(),
+			Duration:       float32(aiAnim.Duration()),
+			TicksPerSecond: float32(aiAnim.TicksPerSecond()),
+			Channels:       channels,
+		})
+	}
+	return animations
+}
+
+// findNode does a depth-first search of root's subtree for a node named
+// name; the raedatoui/assimp binding, unlike assimp's own C++ aiNode, has no
+// FindNode helper.
+func findNode(root *assimp.Node, name string) *assimp.Node {
+	if root == nil {
+		return nil
+	}
+	if root.Name() == name {
+		return root
+	}
+	for _, child := range root.Children() {
+		if found := findNode(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// nodeWorldTransform returns node's accumulated parent-to-node transform, by
+// walking Parent() up to the scene root and composing Transformation()
+// (which assimp stores relative to each node's parent) from the root down.
+// Returns the identity matrix if node is nil.
+func nodeWorldTransform(node *assimp.Node) mgl.Mat4 {
+	var chain []mgl.Mat4
+	for n := node; n != nil; n = n.Parent() {
+		chain = append(chain, toMat4(n.Transformation()))
+	}
+
+	world := mgl.Ident4()
+	for i := len(chain) - 1; i >= 0; i-- {
+		world = world.Mul4(chain[i])
+	}
+	return world
+}
+
+func toVec3Slice(src []assimp.Vector3) []mgl.Vec3 {
+	if len(src) == 0 {
+		return nil
+	}
+	out := make([]mgl.Vec3, len(src))
+	for i, v := range src {
+		out[i] = mgl.Vec3{v.X(), v.Y(), v.Z()}
+	}
+	return out
+}
+
+// toMat4 converts an assimp.Matrix4x4 -- row-major, per Values() -- into a
+// column-major mgl.Mat4.
+func toMat4(m assimp.Matrix4x4) mgl.Mat4 {
+	rows := m.Values()
+	var out mgl.Mat4
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			out[col*4+row] = rows[row][col]
+		}
+	}
+	return out
+}
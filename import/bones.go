@@ -0,0 +1,114 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package importer
+
+import (
+	"sort"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/gombz"
+)
+
+// sceneNode is the minimal per-node information a scene graph walk needs to
+// produce gombz.Bones; each format-specific importer adapts its own node
+// type into these before calling assignBoneIDs.
+type sceneNode struct {
+	Name      string
+	Parent    string // empty for the root
+	Offset    mgl.Mat4
+	Transform mgl.Mat4
+}
+
+// assignBoneIDs walks nodes and returns gombz.Bone values with stable Ids
+// assigned in parent-before-child order, which is the order gombz.Animator
+// expects a skeleton's bones to be in.
+func assignBoneIDs(nodes []sceneNode) []gombz.Bone {
+	indexByName := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		indexByName[n.Name] = i
+	}
+
+	depth := make([]int, len(nodes))
+	var depthOf func(i int) int
+	depthOf = func(i int) int {
+		if depth[i] != 0 {
+			return depth[i]
+		}
+		n := nodes[i]
+		parentIndex, hasParent := indexByName[n.Parent]
+		if n.Parent == "" || !hasParent {
+			depth[i] = 1
+			return depth[i]
+		}
+		depth[i] = depthOf(parentIndex) + 1
+		return depth[i]
+	}
+
+	order := make([]int, len(nodes))
+	for i := range nodes {
+		depthOf(i)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return depth[order[a]] < depth[order[b]]
+	})
+
+	idByName := make(map[string]int32, len(nodes))
+	for id, i := range order {
+		idByName[nodes[i].Name] = int32(id)
+	}
+
+	bones := make([]gombz.Bone, len(nodes))
+	for id, i := range order {
+		n := nodes[i]
+		parent := int32(-1)
+		if n.Parent != "" {
+			if pid, ok := idByName[n.Parent]; ok {
+				parent = pid
+			}
+		}
+		bones[id] = gombz.Bone{
+			Name:      n.Name,
+			Id:        int32(id),
+			Parent:    parent,
+			Offset:    n.Offset,
+			Transform: n.Transform,
+		}
+	}
+	return bones
+}
+
+// vertexInfluence is one (bone, weight) pair affecting a vertex, before the
+// top-4/renormalize step VertexWeightIds/VertexWeights require.
+type vertexInfluence struct {
+	BoneID int32
+	Weight float32
+}
+
+// topFourWeights keeps a vertex's four strongest bone influences and
+// renormalizes their weights to sum to 1, which is what a shader reading
+// VertexWeightIds/VertexWeights expects. Unused slots are left as id/weight 0.
+func topFourWeights(influences []vertexInfluence) (ids, weights mgl.Vec4) {
+	sort.Slice(influences, func(i, j int) bool {
+		return influences[i].Weight > influences[j].Weight
+	})
+	if len(influences) > 4 {
+		influences = influences[:4]
+	}
+
+	var total float32
+	for _, inf := range influences {
+		total += inf.Weight
+	}
+
+	for i, inf := range influences {
+		w := inf.Weight
+		if total > 0 {
+			w /= total
+		}
+		ids[i] = float32(inf.BoneID)
+		weights[i] = w
+	}
+	return ids, weights
+}
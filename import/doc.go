@@ -0,0 +1,13 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+// Package importer ingests common DCC-exported formats -- COLLADA and FBX
+// via assimp bindings (see assimp.go, built with the "assimp" build tag),
+// and native glTF 2.0 (see gltf.go) -- and produces a fully populated
+// gombz.Mesh: Bones, VertexWeightIds/VertexWeights, UVChannels, Tangents
+// and Animations.
+//
+// The package is named "importer" rather than "import", which the feature
+// it implements is informally called, because "import" is a reserved word
+// in Go.
+package importer
@@ -0,0 +1,405 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package importer
+
+import (
+	"fmt"
+
+	"github.com/qmuntal/gltf"
+	"github.com/qmuntal/gltf/modeler"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/gombz"
+)
+
+// ImportGLTF loads a glTF 2.0 document (.gltf or .glb) from path and
+// converts its default scene's first mesh-carrying node hierarchy into a
+// gombz.Mesh, including skinning data and animations if the document
+// defines them.
+func ImportGLTF(path string) (*gombz.Mesh, error) {
+	doc, err := gltf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("importer: failed to open %q: %v", path, err)
+	}
+
+	meshNode, meshIndex, err := findFirstMeshNode(doc)
+	if err != nil {
+		return nil, err
+	}
+	primitive := doc.Meshes[meshIndex].Primitives[0]
+
+	positions, err := modeler.ReadPosition(doc, doc.Accessors[primitive.Attributes[gltf.POSITION]], nil)
+	if err != nil {
+		return nil, fmt.Errorf("importer: failed to read positions: %v", err)
+	}
+	indices, err := modeler.ReadIndices(doc, doc.Accessors[*primitive.Indices], nil)
+	if err != nil {
+		return nil, fmt.Errorf("importer: failed to read indices: %v", err)
+	}
+
+	mesh := new(gombz.Mesh)
+	mesh.Vertices = make([]mgl.Vec3, len(positions))
+	for i, p := range positions {
+		mesh.Vertices[i] = mgl.Vec3{p[0], p[1], p[2]}
+	}
+	mesh.VertexCount = uint32(len(mesh.Vertices))
+
+	if accessorIndex, ok := primitive.Attributes[gltf.NORMAL]; ok {
+		normals, err := modeler.ReadNormal(doc, doc.Accessors[accessorIndex], nil)
+		if err != nil {
+			return nil, fmt.Errorf("importer: failed to read normals: %v", err)
+		}
+		mesh.Normals = make([]mgl.Vec3, len(normals))
+		for i, n := range normals {
+			mesh.Normals[i] = mgl.Vec3{n[0], n[1], n[2]}
+		}
+	}
+
+	if accessorIndex, ok := primitive.Attributes[gltf.TANGENT]; ok {
+		tangents, err := modeler.ReadTangent(doc, doc.Accessors[accessorIndex], nil)
+		if err != nil {
+			return nil, fmt.Errorf("importer: failed to read tangents: %v", err)
+		}
+		mesh.Tangents = make([]mgl.Vec3, len(tangents))
+		for i, t := range tangents {
+			mesh.Tangents[i] = mgl.Vec3{t[0], t[1], t[2]}
+		}
+	}
+
+	for channel := 0; channel < gombz.MaxUVChannelCount; channel++ {
+		attr := fmt.Sprintf("TEXCOORD_%d", channel)
+		accessorIndex, ok := primitive.Attributes[attr]
+		if !ok {
+			break
+		}
+		uvs, err := modeler.ReadTextureCoord(doc, doc.Accessors[accessorIndex], nil)
+		if err != nil {
+			return nil, fmt.Errorf("importer: failed to read %s: %v", attr, err)
+		}
+		mesh.UVChannels[channel] = make([]mgl.Vec2, len(uvs))
+		for i, uv := range uvs {
+			mesh.UVChannels[channel][i] = mgl.Vec2{uv[0], uv[1]}
+		}
+	}
+
+	mesh.Faces = make([]gombz.MeshFace, len(indices)/3)
+	for i := range mesh.Faces {
+		mesh.Faces[i] = gombz.MeshFace{indices[i*3], indices[i*3+1], indices[i*3+2]}
+	}
+	mesh.FaceCount = uint32(len(mesh.Faces))
+
+	if len(mesh.Tangents) == 0 && len(mesh.UVChannels[0]) > 0 {
+		computeTangents(mesh)
+	}
+
+	if meshNode.Skin != nil {
+		if err := importGLTFSkin(doc, *meshNode.Skin, primitive, mesh); err != nil {
+			return nil, err
+		}
+		mesh.BoneCount = uint32(len(mesh.Bones))
+
+		mesh.Animations, err = importGLTFAnimations(doc, mesh)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mesh, nil
+}
+
+// findFirstMeshNode returns the first node in doc's default scene (scene 0
+// if the document doesn't name a default) that references a mesh, along
+// with that mesh's index.
+func findFirstMeshNode(doc *gltf.Document) (*gltf.Node, int, error) {
+	sceneIndex := 0
+	if doc.Scene != nil {
+		sceneIndex = *doc.Scene
+	}
+
+	for _, nodeIndex := range doc.Scenes[sceneIndex].Nodes {
+		node := doc.Nodes[nodeIndex]
+		if node.Mesh != nil {
+			return node, *node.Mesh, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("importer: no mesh found in the document's default scene")
+}
+
+// importGLTFSkin converts a glTF skin into gombz.Bones and per-vertex
+// skinning weights, renormalizing each vertex down to its top four
+// influences.
+func importGLTFSkin(doc *gltf.Document, skinIndex int, primitive *gltf.Primitive, mesh *gombz.Mesh) error {
+	skin := doc.Skins[skinIndex]
+
+	inverseBind, err := modeler.ReadInverseBindMatrices(doc, doc.Accessors[*skin.InverseBindMatrices], nil)
+	if err != nil {
+		return fmt.Errorf("importer: failed to read inverse bind matrices: %v", err)
+	}
+
+	nodes := make([]sceneNode, len(skin.Joints))
+	for i, jointNodeIndex := range skin.Joints {
+		node := doc.Nodes[jointNodeIndex]
+		nodes[i] = sceneNode{
+			Name:      nodeName(node, jointNodeIndex),
+			Parent:    parentJointName(doc, skin.Joints, jointNodeIndex),
+			Offset:    mat4FromGLTF(inverseBind[i]),
+			Transform: localTransform(node),
+		}
+	}
+	mesh.Bones = assignBoneIDs(nodes)
+
+	jointIndexToBoneID := make(map[int]int32, len(skin.Joints))
+	for _, jointNodeIndex := range skin.Joints {
+		name := nodeName(doc.Nodes[jointNodeIndex], jointNodeIndex)
+		jointIndexToBoneID[jointNodeIndex] = mesh.Bones[boneIndexForNodeName(mesh.Bones, name)].Id
+	}
+
+	jointsAccessorIndex, hasJoints := primitive.Attributes["JOINTS_0"]
+	weightsAccessorIndex, hasWeights := primitive.Attributes["WEIGHTS_0"]
+	if !hasJoints || !hasWeights {
+		return nil
+	}
+
+	joints, err := modeler.ReadJoints(doc, doc.Accessors[jointsAccessorIndex], nil)
+	if err != nil {
+		return fmt.Errorf("importer: failed to read joint indices: %v", err)
+	}
+	weights, err := modeler.ReadWeights(doc, doc.Accessors[weightsAccessorIndex], nil)
+	if err != nil {
+		return fmt.Errorf("importer: failed to read joint weights: %v", err)
+	}
+
+	mesh.VertexWeightIds = make([]mgl.Vec4, len(mesh.Vertices))
+	mesh.VertexWeights = make([]mgl.Vec4, len(mesh.Vertices))
+	for v := range mesh.Vertices {
+		influences := make([]vertexInfluence, 0, 4)
+		for j := 0; j < 4; j++ {
+			w := weights[v][j]
+			if w <= 0 {
+				continue
+			}
+			jointNodeIndex := skin.Joints[joints[v][j]]
+			influences = append(influences, vertexInfluence{
+				BoneID: jointIndexToBoneID[jointNodeIndex],
+				Weight: w,
+			})
+		}
+		mesh.VertexWeightIds[v], mesh.VertexWeights[v] = topFourWeights(influences)
+	}
+
+	return nil
+}
+
+// importGLTFAnimations converts every animation in doc whose channels
+// target one of mesh's bones into a gombz.Animation.
+func importGLTFAnimations(doc *gltf.Document, mesh *gombz.Mesh) ([]gombz.Animation, error) {
+	boneNames := make(map[int]string, len(mesh.Bones))
+	for nodeIndex, node := range doc.Nodes {
+		name := nodeName(node, nodeIndex)
+		for _, b := range mesh.Bones {
+			if b.Name == name {
+				boneNames[nodeIndex] = name
+				break
+			}
+		}
+	}
+
+	animations := make([]gombz.Animation, 0, len(doc.Animations))
+	for _, anim := range doc.Animations {
+		channelsByBone := make(map[string]*gombz.AnimationChannel)
+		var duration float32
+
+		for _, ch := range anim.Channels {
+			if ch.Target.Node == nil {
+				continue
+			}
+			boneName, ok := boneNames[*ch.Target.Node]
+			if !ok {
+				continue
+			}
+			gc, ok := channelsByBone[boneName]
+			if !ok {
+				gc = &gombz.AnimationChannel{Name: boneName}
+				channelsByBone[boneName] = gc
+			}
+
+			sampler := anim.Samplers[ch.Sampler]
+			times, err := readAccessorScalarFloats(doc, sampler.Input)
+			if err != nil {
+				return nil, fmt.Errorf("importer: failed to read keyframe times: %v", err)
+			}
+			if len(times) > 0 && times[len(times)-1] > duration {
+				duration = times[len(times)-1]
+			}
+
+			switch ch.Target.Path {
+			case gltf.TRSTranslation:
+				values, err := readAccessorVec3s(doc, sampler.Output)
+				if err != nil {
+					return nil, fmt.Errorf("importer: failed to read translation keys: %v", err)
+				}
+				for i, t := range times {
+					gc.PositionKeys = append(gc.PositionKeys, gombz.AnimationVec3Key{Time: t, Key: values[i]})
+				}
+			case gltf.TRSScale:
+				values, err := readAccessorVec3s(doc, sampler.Output)
+				if err != nil {
+					return nil, fmt.Errorf("importer: failed to read scale keys: %v", err)
+				}
+				for i, t := range times {
+					gc.ScaleKeys = append(gc.ScaleKeys, gombz.AnimationVec3Key{Time: t, Key: values[i]})
+				}
+			case gltf.TRSRotation:
+				values, err := readAccessorQuats(doc, sampler.Output)
+				if err != nil {
+					return nil, fmt.Errorf("importer: failed to read rotation keys: %v", err)
+				}
+				for i, t := range times {
+					gc.RotationKeys = append(gc.RotationKeys, gombz.AnimationQuatKey{Time: t, Key: values[i]})
+				}
+			}
+		}
+
+		channels := make([]gombz.AnimationChannel, 0, len(channelsByBone))
+		for _, gc := range channelsByBone {
+			channels = append(channels, *gc)
+		}
+
+		animations = append(animations, gombz.Animation{
+			Name:           anim.Name,
+			Duration:       duration,
+			TicksPerSecond: 1, // glTF keyframe times are already in seconds
+			Channels:       channels,
+		})
+	}
+
+	return animations, nil
+}
+
+// readAccessorScalarFloats reads accessorIndex as a SCALAR float accessor,
+// which is the type glTF uses for animation sampler keyframe times.
+func readAccessorScalarFloats(doc *gltf.Document, accessorIndex int) ([]float32, error) {
+	acr := doc.Accessors[accessorIndex]
+	data, err := modeler.ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := data.([]float32)
+	if !ok {
+		return nil, fmt.Errorf("importer: accessor %d is not a SCALAR float accessor", accessorIndex)
+	}
+	return values, nil
+}
+
+// readAccessorVec3s reads accessorIndex as a VEC3 float accessor, which is
+// the type glTF uses for translation and scale animation sampler output.
+func readAccessorVec3s(doc *gltf.Document, accessorIndex int) ([]mgl.Vec3, error) {
+	acr := doc.Accessors[accessorIndex]
+	data, err := modeler.ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := data.([][3]float32)
+	if !ok {
+		return nil, fmt.Errorf("importer: accessor %d is not a VEC3 float accessor", accessorIndex)
+	}
+	out := make([]mgl.Vec3, len(raw))
+	for i, v := range raw {
+		out[i] = mgl.Vec3{v[0], v[1], v[2]}
+	}
+	return out, nil
+}
+
+// readAccessorQuats reads accessorIndex as a VEC4 float accessor, which is
+// the type glTF uses for rotation animation sampler output, stored as
+// (x, y, z, w).
+func readAccessorQuats(doc *gltf.Document, accessorIndex int) ([]mgl.Quat, error) {
+	acr := doc.Accessors[accessorIndex]
+	data, err := modeler.ReadAccessor(doc, acr, nil)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := data.([][4]float32)
+	if !ok {
+		return nil, fmt.Errorf("importer: accessor %d is not a VEC4 float accessor", accessorIndex)
+	}
+	out := make([]mgl.Quat, len(raw))
+	for i, v := range raw {
+		out[i] = mgl.Quat{W: v[3], V: mgl.Vec3{v[0], v[1], v[2]}}
+	}
+	return out, nil
+}
+
+// nodeName returns node's name, falling back to a positional placeholder
+// for the (rare) unnamed node, since gombz.Bone.Name is how skinning weights
+// and animation channels are matched back to a bone.
+func nodeName(node *gltf.Node, index int) string {
+	if node.Name != "" {
+		return node.Name
+	}
+	return fmt.Sprintf("node_%d", index)
+}
+
+// parentJointName returns the name of jointNodeIndex's parent, restricted to
+// the joints that belong to this skin -- any ancestor outside the skin is
+// treated as the bone root.
+func parentJointName(doc *gltf.Document, joints []int, jointNodeIndex int) string {
+	jointSet := make(map[int]bool, len(joints))
+	for _, j := range joints {
+		jointSet[j] = true
+	}
+	for nodeIndex, node := range doc.Nodes {
+		if !jointSet[nodeIndex] {
+			continue
+		}
+		for _, child := range node.Children {
+			if child == jointNodeIndex {
+				return nodeName(node, nodeIndex)
+			}
+		}
+	}
+	return ""
+}
+
+// mat4FromGLTF converts a glTF inverse-bind matrix -- decoded by the modeler
+// package as 4 columns of 4 floats -- into a column-major mgl.Mat4.
+func mat4FromGLTF(m [4][4]float32) mgl.Mat4 {
+	return mgl.Mat4{
+		m[0][0], m[0][1], m[0][2], m[0][3],
+		m[1][0], m[1][1], m[1][2], m[1][3],
+		m[2][0], m[2][1], m[2][2], m[2][3],
+		m[3][0], m[3][1], m[3][2], m[3][3],
+	}
+}
+
+// localTransform returns node's transform relative to its parent as a Mat4,
+// composing TRS if the node stores it that way instead of a raw matrix.
+func localTransform(node *gltf.Node) mgl.Mat4 {
+	matrix := node.MatrixOrDefault()
+	if matrix != gltf.DefaultMatrix {
+		var out mgl.Mat4
+		for i, v := range matrix {
+			out[i] = float32(v)
+		}
+		return out
+	}
+
+	t := node.TranslationOrDefault()
+	r := node.RotationOrDefault()
+	s := node.ScaleOrDefault()
+	rotQuat := mgl.Quat{W: float32(r[3]), V: mgl.Vec3{float32(r[0]), float32(r[1]), float32(r[2])}}
+	return mgl.Translate3D(float32(t[0]), float32(t[1]), float32(t[2])).
+		Mul4(rotQuat.Mat4()).
+		Mul4(mgl.Scale3D(float32(s[0]), float32(s[1]), float32(s[2])))
+}
+
+// boneIndexForNodeName returns the index into bones of the Bone named name.
+func boneIndexForNodeName(bones []gombz.Bone, name string) int {
+	for i, b := range bones {
+		if b.Name == name {
+			return i
+		}
+	}
+	return -1
+}
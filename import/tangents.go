@@ -0,0 +1,64 @@
+// Copyright 2015, Timothy Bogdala <tdb@animal-machine.com>
+// See the LICENSE file for more details.
+
+package importer
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/tbogdala/gombz"
+)
+
+// computeTangents fills in mesh.Tangents from positions, normals, the first
+// UV channel and the face list, using the standard per-face accumulation
+// technique (Lengyel's method). This is the fallback for source assets that
+// don't ship tangents -- notably glTF assets with no TANGENT attribute.
+//
+// assimp-sourced assets get their tangents from aiProcess_CalcTangentSpace
+// instead (see assimp.go), but that's the same per-face Lengyel-style
+// accumulation as this fallback, not MikkTSpace -- true MikkTSpace tangent
+// generation isn't implemented by either path. That's a known gap against
+// what this feature originally asked for, not a solved problem: MikkTSpace
+// tangents differ from Lengyel's per-face average, and anything authored
+// against a MikkTSpace-baked normal map may show visible seams here.
+func computeTangents(mesh *gombz.Mesh) {
+	if len(mesh.UVChannels[0]) != len(mesh.Vertices) {
+		return
+	}
+
+	tangents := make([]mgl.Vec3, len(mesh.Vertices))
+	bitangents := make([]mgl.Vec3, len(mesh.Vertices))
+
+	for _, face := range mesh.Faces {
+		i0, i1, i2 := face[0], face[1], face[2]
+		p0, p1, p2 := mesh.Vertices[i0], mesh.Vertices[i1], mesh.Vertices[i2]
+		uv0, uv1, uv2 := mesh.UVChannels[0][i0], mesh.UVChannels[0][i1], mesh.UVChannels[0][i2]
+
+		edge1 := p1.Sub(p0)
+		edge2 := p2.Sub(p0)
+		deltaUV1 := uv1.Sub(uv0)
+		deltaUV2 := uv2.Sub(uv0)
+
+		denom := deltaUV1[0]*deltaUV2[1] - deltaUV2[0]*deltaUV1[1]
+		if denom == 0 {
+			continue
+		}
+		r := 1 / denom
+
+		tangent := edge1.Mul(deltaUV2[1]).Sub(edge2.Mul(deltaUV1[1])).Mul(r)
+		bitangent := edge2.Mul(deltaUV1[0]).Sub(edge1.Mul(deltaUV2[0])).Mul(r)
+
+		for _, i := range face {
+			tangents[i] = tangents[i].Add(tangent)
+			bitangents[i] = bitangents[i].Add(bitangent)
+		}
+	}
+
+	mesh.Tangents = make([]mgl.Vec3, len(mesh.Vertices))
+	for i, n := range mesh.Normals {
+		t := tangents[i].Sub(n.Mul(n.Dot(tangents[i]))).Normalize()
+		if n.Cross(t).Dot(bitangents[i]) < 0 {
+			t = t.Mul(-1)
+		}
+		mesh.Tangents[i] = t
+	}
+}